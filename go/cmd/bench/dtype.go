@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Dataset files carry an optional 4-byte magic header identifying the
+// element type, followed by a uint32 count and the payload. Legacy
+// int32 datasets predate the magic header and start straight at the
+// count, so readBinInt32LE and mmapReadInt32LE fall back to that when
+// the magic doesn't match.
+const (
+	magicInt32   = "S32L"
+	magicInt64   = "S64L"
+	magicUint64  = "U64L"
+	magicFloat64 = "F64L"
+	magicString  = "STRV"
+)
+
+// detectDtypeMagic peeks at a dataset's magic header and maps it to a
+// --dtype value. It returns "" (no error) for legacy files with no
+// recognized magic, so callers can fall back to the --dtype default.
+func detectDtypeMagic(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var magic [4]byte
+	if _, err := io.ReadFull(f, magic[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return "", nil
+		}
+		return "", err
+	}
+
+	switch string(magic[:]) {
+	case magicInt32:
+		return "int32", nil
+	case magicInt64:
+		return "int64", nil
+	case magicUint64:
+		return "uint64", nil
+	case magicFloat64:
+		return "float64", nil
+	case magicString:
+		return "string", nil
+	default:
+		return "", nil
+	}
+}
+
+func openPastMagic(path, magic string) (*os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var header [4]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(header[:]) != magic {
+		f.Close()
+		return nil, fmt.Errorf("dataset %s: expected magic %q, got %q", path, magic, string(header[:]))
+	}
+	return f, nil
+}
+
+func loadInt64Dataset(path string) ([]int64, error) {
+	f, err := openPastMagic(path, magicInt64)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var n uint32
+	if err := binary.Read(f, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+	values := make([]int64, n)
+	if err := binary.Read(f, binary.LittleEndian, &values); err != nil {
+		return nil, fmt.Errorf("read payload: %w", err)
+	}
+	return values, nil
+}
+
+func loadUint64Dataset(path string) ([]uint64, error) {
+	f, err := openPastMagic(path, magicUint64)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var n uint32
+	if err := binary.Read(f, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+	values := make([]uint64, n)
+	if err := binary.Read(f, binary.LittleEndian, &values); err != nil {
+		return nil, fmt.Errorf("read payload: %w", err)
+	}
+	return values, nil
+}
+
+func loadFloat64Dataset(path string) ([]float64, error) {
+	f, err := openPastMagic(path, magicFloat64)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var n uint32
+	if err := binary.Read(f, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+	values := make([]float64, n)
+	if err := binary.Read(f, binary.LittleEndian, &values); err != nil {
+		return nil, fmt.Errorf("read payload: %w", err)
+	}
+	return values, nil
+}
+
+// loadStringDataset reads STRV's length-prefixed string payload: a
+// uint32 count followed by, for each string, a uint32 byte length and
+// the raw UTF-8 bytes.
+func loadStringDataset(path string) ([]string, error) {
+	f, err := openPastMagic(path, magicString)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+
+	var n uint32
+	if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+
+	values := make([]string, n)
+	for i := range values {
+		var l uint32
+		if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
+			return nil, fmt.Errorf("read string %d length: %w", i, err)
+		}
+		buf := make([]byte, l)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, fmt.Errorf("read string %d bytes: %w", i, err)
+		}
+		values[i] = string(buf)
+	}
+	return values, nil
+}
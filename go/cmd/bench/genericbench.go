@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Benchmark bundles a dataset with the comparison used to sort and
+// validate it, so runGenericBenchmark doesn't need a type switch per
+// dtype. The existing int32 path predates generics and keeps its own
+// algoRegistry-driven loop in runAlgo; Benchmark covers the dtypes
+// that only get the builtin sort.
+type Benchmark[T any] struct {
+	Values []T
+	Less   func(a, b T) bool
+}
+
+func (b Benchmark[T]) sortedNonDecreasing(a []T) bool {
+	for i := 0; i < len(a)-1; i++ {
+		if b.Less(a[i+1], a[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// runGenericDtype loads the dataset for a non-int32 dtype and runs it
+// through runGenericBenchmark.
+func runGenericDtype(dtype, dataset, dist, lang, langVer string, validate bool, mode metricsMode, warmup, reps int, out, outFormat string, acc *summaryAccumulator, completed resumeSet, progressEvery int) error {
+	switch dtype {
+	case "int64":
+		values, err := loadInt64Dataset(dataset)
+		if err != nil {
+			return err
+		}
+		bench := Benchmark[int64]{Values: values, Less: func(a, b int64) bool { return a < b }}
+		runGenericBenchmark(dtype, bench, dataset, dist, lang, langVer, validate, mode, warmup, reps, out, outFormat, acc, completed, progressEvery)
+	case "uint64":
+		values, err := loadUint64Dataset(dataset)
+		if err != nil {
+			return err
+		}
+		bench := Benchmark[uint64]{Values: values, Less: func(a, b uint64) bool { return a < b }}
+		runGenericBenchmark(dtype, bench, dataset, dist, lang, langVer, validate, mode, warmup, reps, out, outFormat, acc, completed, progressEvery)
+	case "float64":
+		values, err := loadFloat64Dataset(dataset)
+		if err != nil {
+			return err
+		}
+		bench := Benchmark[float64]{Values: values, Less: func(a, b float64) bool { return a < b }}
+		runGenericBenchmark(dtype, bench, dataset, dist, lang, langVer, validate, mode, warmup, reps, out, outFormat, acc, completed, progressEvery)
+	case "string":
+		values, err := loadStringDataset(dataset)
+		if err != nil {
+			return err
+		}
+		bench := Benchmark[string]{Values: values, Less: func(a, b string) bool { return a < b }}
+		runGenericBenchmark(dtype, bench, dataset, dist, lang, langVer, validate, mode, warmup, reps, out, outFormat, acc, completed, progressEvery)
+	default:
+		return fmt.Errorf("unknown --dtype %q (known: int32, int64, uint64, float64, string)", dtype)
+	}
+	return nil
+}
+
+func runGenericBenchmark[T any](dtype string, bench Benchmark[T], dataset, dist, lang, langVer string, validate bool, mode metricsMode, warmup, reps int, out, outFormat string, acc *summaryAccumulator, completed resumeSet, progressEvery int) {
+	n := len(bench.Values)
+	sortFn := func(a []T) {
+		sort.Slice(a, func(i, j int) bool { return bench.Less(a[i], a[j]) })
+	}
+
+	// Warmup
+	for i := 0; i < warmup; i++ {
+		tmp := make([]T, n)
+		copy(tmp, bench.Values)
+		sortFn(tmp)
+	}
+
+	tracker := newProgressTracker(20)
+	done := 0
+
+	// Measured
+	for rep := 0; rep < reps; rep++ {
+		if completed.has(dataset, "builtin", rep) {
+			done++
+			continue
+		}
+
+		tmp := make([]T, n)
+		copy(tmp, bench.Values)
+
+		before := captureSnapshot(mode)
+		t0 := time.Now()
+		sortFn(tmp)
+		elapsed := time.Since(t0)
+		after := captureSnapshot(mode)
+		rm := diffSnapshots(before, after, mode)
+
+		ok := true
+		if validate {
+			ok = bench.sortedNonDecreasing(tmp)
+		}
+
+		timeMs := float64(elapsed.Nanoseconds()) / 1_000_000.0
+
+		row := []string{
+			nowISO(),
+			"sort",
+			lang,
+			langVer,
+			dtype,
+			"builtin",
+			dataset,
+			dist,
+			fmt.Sprintf("%d", n),
+			fmt.Sprintf("%d", warmup),
+			fmt.Sprintf("%d", rep),
+			fmt.Sprintf("%.3f", timeMs),
+			boolStr(ok),
+			metricField(rm.have, "%.3f", rm.userCPUMs),
+			metricField(rm.have, "%.3f", rm.sysCPUMs),
+			metricField(rm.have, "%d", rm.maxRSSKB),
+			metricField(rm.haveMem, "%d", rm.heapAllocDeltaBytes),
+			metricField(rm.haveMem, "%.3f", rm.gcPauseMs),
+			metricField(rm.haveMem, "%d", rm.numGC),
+		}
+
+		fmt.Println(strings.Join(row, ","))
+		if err := appendRow(out, outFormat, row); err != nil {
+			fmt.Fprintln(os.Stderr, "write output:", err)
+			os.Exit(1)
+		}
+		acc.add("builtin", dataset, timeMs)
+
+		tracker.add(timeMs)
+		done++
+		if progressEvery > 0 && done%progressEvery == 0 {
+			reportProgress(dataset, "builtin", done, reps, tracker)
+		}
+	}
+}
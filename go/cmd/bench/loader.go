@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// readBinInt32LE is the default loader: it allocates a full []int32 and
+// decodes the payload with binary.Read. It accepts both the legacy
+// format (count, payload) and the S32L-prefixed format shared with the
+// other dtypes.
+func readBinInt32LE(path string) ([]int32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var header [4]byte
+	if _, err := io.ReadFull(f, header[:]); err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if string(header[:]) != magicInt32 {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+	}
+
+	var n uint32
+	if err := binary.Read(f, binary.LittleEndian, &n); err != nil {
+		return nil, fmt.Errorf("read count: %w", err)
+	}
+
+	values := make([]int32, n)
+	if err := binary.Read(f, binary.LittleEndian, &values); err != nil {
+		return nil, fmt.Errorf("read payload: %w", err)
+	}
+
+	return values, nil
+}
+
+// loadInt32s loads a dataset with the named loader ("read" or "mmap")
+// and returns the values plus a closer that must be called once the
+// benchmark is done with them (a no-op for the "read" loader).
+func loadInt32s(loader, path string) ([]int32, io.Closer, error) {
+	switch loader {
+	case "", "read":
+		values, err := readBinInt32LE(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return values, io.NopCloser(nil), nil
+	case "mmap":
+		return mmapReadInt32LE(path)
+	default:
+		return nil, nil, fmt.Errorf("unknown --loader %q (known: read, mmap)", loader)
+	}
+}
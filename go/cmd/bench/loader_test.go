@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestDataset(t *testing.T, values []int32) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "dataset.bin")
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create dataset: %v", err)
+	}
+	defer f.Close()
+
+	if err := binary.Write(f, binary.LittleEndian, uint32(len(values))); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	if err := binary.Write(f, binary.LittleEndian, values); err != nil {
+		t.Fatalf("write payload: %v", err)
+	}
+	return path
+}
+
+func TestMmapAndReadLoadersAgree(t *testing.T) {
+	values := []int32{5, 3, 1, 4, 1, 5, 9, 2, 6, -100, 2147483647, -2147483648, 0}
+	path := writeTestDataset(t, values)
+
+	read, err := readBinInt32LE(path)
+	if err != nil {
+		t.Fatalf("readBinInt32LE: %v", err)
+	}
+
+	mmapped, closer, err := mmapReadInt32LE(path)
+	if err != nil {
+		t.Fatalf("mmapReadInt32LE: %v", err)
+	}
+	defer closer.Close()
+
+	if len(read) != len(mmapped) {
+		t.Fatalf("length mismatch: read=%d mmap=%d", len(read), len(mmapped))
+	}
+	for i := range read {
+		if read[i] != mmapped[i] {
+			t.Fatalf("value mismatch at %d: read=%d mmap=%d", i, read[i], mmapped[i])
+		}
+	}
+}
+
+func TestMmapEmptyDataset(t *testing.T) {
+	path := writeTestDataset(t, nil)
+
+	values, closer, err := mmapReadInt32LE(path)
+	if err != nil {
+		t.Fatalf("mmapReadInt32LE: %v", err)
+	}
+	defer closer.Close()
+
+	if len(values) != 0 {
+		t.Fatalf("expected empty slice, got %d values", len(values))
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !windows
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// mmapCloser munmaps the backing pages on Close. values aliases data,
+// so it must not be used after Close is called.
+type mmapCloser struct {
+	data []byte
+}
+
+func (m *mmapCloser) Close() error {
+	if m.data == nil {
+		return nil
+	}
+	err := syscall.Munmap(m.data)
+	m.data = nil
+	return err
+}
+
+// mmapReadInt32LE maps the dataset read-only and views the payload as
+// []int32 in place, avoiding the full-file copy that readBinInt32LE
+// pays for. The returned slice aliases the mapping and is only valid
+// until Close is called on the returned io.Closer.
+func mmapReadInt32LE(path string) ([]int32, io.Closer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size < 4 {
+		return nil, nil, fmt.Errorf("mmap dataset: file too small for header")
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, fmt.Errorf("mmap dataset: %w", err)
+	}
+
+	offset := 0
+	if len(data) >= 4 && string(data[:4]) == magicInt32 {
+		offset = 4
+	}
+	if len(data) < offset+4 {
+		syscall.Munmap(data)
+		return nil, nil, fmt.Errorf("mmap dataset: file too small for count")
+	}
+
+	n := binary.LittleEndian.Uint32(data[offset : offset+4])
+	payload := data[offset+4:]
+	if uint64(len(payload)) < uint64(n)*4 {
+		syscall.Munmap(data)
+		return nil, nil, fmt.Errorf("mmap dataset: truncated payload")
+	}
+
+	closer := &mmapCloser{data: data}
+	if n == 0 {
+		return []int32{}, closer, nil
+	}
+	values := unsafe.Slice((*int32)(unsafe.Pointer(&payload[0])), n)
+	return values, closer, nil
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+import "io"
+
+// mmapReadInt32LE would normally map the dataset via CreateFileMapping
+// / MapViewOfFile, but that path isn't wired up yet on Windows. Fall
+// back to the plain read loader so --loader=mmap still works, just
+// without the allocation savings.
+func mmapReadInt32LE(path string) ([]int32, io.Closer, error) {
+	values, err := readBinInt32LE(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return values, io.NopCloser(nil), nil
+}
@@ -1,8 +1,8 @@
 package main
 
 import (
-	"encoding/binary"
 	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -35,7 +35,45 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-func appendRow(csvPath string, row []string) error {
+// rawHeader names the columns of a raw per-rep row, in order. Both the
+// CSV and NDJSON sinks key off it so they stay in lockstep.
+var rawHeader = []string{
+	"timestamp_iso",
+	"task",
+	"language",
+	"language_version",
+	"dtype",
+	"algo",
+	"dataset_file",
+	"distribution",
+	"n",
+	"warmup_runs",
+	"rep_idx",
+	"time_ms",
+	"ok",
+	"user_cpu_ms",
+	"sys_cpu_ms",
+	"max_rss_kb",
+	"heap_alloc_delta_bytes",
+	"gc_pause_ms",
+	"num_gc",
+}
+
+// appendRow writes one raw per-rep row to path in the given format
+// ("csv", the default, or "ndjson"), creating the file and any parent
+// directories on first use.
+func appendRow(path, format string, row []string) error {
+	switch format {
+	case "", "csv":
+		return appendRowCSV(path, row)
+	case "ndjson":
+		return appendRowNDJSON(path, row)
+	default:
+		return fmt.Errorf("unknown --out-format %q (known: csv, ndjson)", format)
+	}
+}
+
+func appendRowCSV(csvPath string, row []string) error {
 	if err := ensureParentDir(csvPath); err != nil {
 		return err
 	}
@@ -51,21 +89,7 @@ func appendRow(csvPath string, row []string) error {
 	defer w.Flush()
 
 	if newFile {
-		header := []string{
-			"timestamp_iso",
-			"task",
-			"language",
-			"language_version",
-			"algo",
-			"dataset_file",
-			"distribution",
-			"n",
-			"warmup_runs",
-			"rep_idx",
-			"time_ms",
-			"ok",
-		}
-		if err := w.Write(header); err != nil {
+		if err := w.Write(rawHeader); err != nil {
 			return err
 		}
 	}
@@ -73,24 +97,43 @@ func appendRow(csvPath string, row []string) error {
 	return w.Write(row)
 }
 
-func readBinInt32LE(path string) ([]int32, error) {
-	f, err := os.Open(path)
+// appendRowNDJSON writes one JSON object per line, zipping rawHeader
+// against row so every line is self-describing without a header row.
+func appendRowNDJSON(path string, row []string) error {
+	if err := ensureParentDir(path); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer f.Close()
 
-	var n uint32
-	if err := binary.Read(f, binary.LittleEndian, &n); err != nil {
-		return nil, fmt.Errorf("read header: %w", err)
+	obj := make(map[string]string, len(rawHeader))
+	for i, col := range rawHeader {
+		if i < len(row) {
+			obj[col] = row[i]
+		}
 	}
 
-	values := make([]int32, n)
-	if err := binary.Read(f, binary.LittleEndian, &values); err != nil {
-		return nil, fmt.Errorf("read payload: %w", err)
+	line, err := json.Marshal(obj)
+	if err != nil {
+		return err
 	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func boolStr(ok bool) string {
+	if ok {
+		return "true"
+	}
+	return "false"
+}
 
-	return values, nil
+func sortInt32sStd(a []int32) {
+	sort.Slice(a, func(i, j int) bool { return a[i] < a[j] })
 }
 
 func isSortedNonDecreasing(a []int32) bool {
@@ -103,55 +146,202 @@ func isSortedNonDecreasing(a []int32) bool {
 }
 
 func main() {
-	dataset := flag.String("dataset", "", "Path to .bin dataset")
-	algo := flag.String("algo", "builtin", "Sorting algorithm (builtin)")
+	dataset := flag.String("dataset", "", "Path to a single .bin dataset")
+	datasetDir := flag.String("dataset-dir", "", "Directory to sweep for datasets matching --pattern (alternative to --dataset)")
+	pattern := flag.String("pattern", "*.bin", "Glob (matched against the base filename) used with --dataset-dir")
+	shuffle := flag.Bool("shuffle", false, "Randomize dataset processing order under --dataset-dir")
+	resume := flag.Bool("resume", false, "Skip (dataset, algo, rep) tuples already present in --out")
+	progressEvery := flag.Int("progress-every", 10, "Emit a progress/ETA line to stderr every N reps, counting reps skipped by --resume (0 disables)")
+	algo := flag.String("algo", "builtin", "Sorting algorithm: builtin, radix, pdq, heap, parmerge, or all")
 	warmup := flag.Int("warmup", 5, "Warmup runs (not recorded)")
 	reps := flag.Int("reps", 30, "Measured repetitions")
 	out := flag.String("out", "results/raw.csv", "CSV output path")
 	noValidate := flag.Bool("no-validate", false, "Disable sortedness validation")
+	loader := flag.String("loader", "read", "Dataset loader: read or mmap")
+	metricsFlag := flag.String("metrics", "none", "Per-rep metrics: none, basic, or full")
+	dtypeFlag := flag.String("dtype", "int32", "Dataset element type: int32, int64, uint64, float64, or string")
+	outFormat := flag.String("out-format", "csv", "Raw per-rep sink format: csv or ndjson")
+	summary := flag.Bool("summary", false, "Compute and write a statistical summary after all reps complete")
+	summaryOut := flag.String("summary-out", "results/summary.csv", "Summary output path (.csv or .ndjson)")
 	flag.Parse()
 
-	if *dataset == "" {
-		fmt.Fprintln(os.Stderr, "--dataset is required")
+	mode, ok := parseMetricsMode(*metricsFlag)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown --metrics %q (known: none, basic, full)\n", *metricsFlag)
+		os.Exit(2)
+	}
+	if *outFormat != "csv" && *outFormat != "ndjson" {
+		fmt.Fprintf(os.Stderr, "unknown --out-format %q (known: csv, ndjson)\n", *outFormat)
+		os.Exit(2)
+	}
+
+	if (*dataset == "") == (*datasetDir == "") {
+		fmt.Fprintln(os.Stderr, "exactly one of --dataset or --dataset-dir is required")
 		os.Exit(2)
 	}
 	if *warmup < 0 || *reps <= 0 {
 		fmt.Fprintln(os.Stderr, "warmup must be >= 0 and reps must be > 0")
 		os.Exit(2)
 	}
-	if *algo != "builtin" {
-		fmt.Fprintln(os.Stderr, "only --algo builtin is supported right now")
-		os.Exit(2)
+
+	dtypeExplicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "dtype" {
+			dtypeExplicit = true
+		}
+	})
+
+	var datasets []string
+	if *datasetDir != "" {
+		found, err := discoverDatasets(*datasetDir, *pattern)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "sweep dataset-dir:", err)
+			os.Exit(1)
+		}
+		if len(found) == 0 {
+			fmt.Fprintf(os.Stderr, "no datasets under %s matched pattern %q\n", *datasetDir, *pattern)
+			os.Exit(1)
+		}
+		if *shuffle {
+			shuffleStrings(found)
+		}
+		datasets = found
+	} else {
+		datasets = []string{*dataset}
+	}
+
+	var completed resumeSet
+	if *resume {
+		var err error
+		completed, err = buildResumeSet(*out, *outFormat)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "resume:", err)
+			os.Exit(1)
+		}
+	}
+
+	lang := "go"
+	langVer := runtime.Version()
+	validate := !*noValidate
+
+	var acc *summaryAccumulator
+	if *summary {
+		acc = newSummaryAccumulator()
 	}
 
-	values, err := readBinInt32LE(*dataset)
+	sweeping := len(datasets) > 1
+	for i, path := range datasets {
+		if sweeping {
+			fmt.Fprintf(os.Stderr, "[sweep] dataset %d/%d: %s\n", i+1, len(datasets), path)
+		}
+		runDataset(path, *algo, *dtypeFlag, dtypeExplicit, *loader, lang, langVer, validate, mode, *warmup, *reps, *out, *outFormat, acc, completed, *progressEvery)
+	}
+	writeSummaryIfEnabled(acc, *summaryOut)
+}
+
+// runDataset resolves dtype and algo selection for a single dataset
+// path and runs the measured benchmark, dispatching to the int32
+// algoRegistry path or the generic Benchmark[T] path as appropriate.
+func runDataset(dataset, algo, dtypeFlag string, dtypeExplicit bool, loader, lang, langVer string, validate bool, mode metricsMode, warmup, reps int, out, outFormat string, acc *summaryAccumulator, completed resumeSet, progressEvery int) {
+	dtype, err := resolveDtype(dataset, dtypeFlag, dtypeExplicit)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "dtype:", err)
+		os.Exit(1)
+	}
+
+	dist := inferDistribution(dataset)
+
+	if dtype != "int32" {
+		if algo != "builtin" && algo != "all" {
+			fmt.Fprintf(os.Stderr, "--algo %q is only available for --dtype int32; %s only supports builtin\n", algo, dtype)
+			os.Exit(2)
+		}
+		if err := runGenericDtype(dtype, dataset, dist, lang, langVer, validate, mode, warmup, reps, out, outFormat, acc, completed, progressEvery); err != nil {
+			fmt.Fprintln(os.Stderr, "read dataset:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var algos []string
+	if algo == "all" {
+		algos = algoOrder
+	} else {
+		if _, ok := algoRegistry[algo]; !ok {
+			fmt.Fprintf(os.Stderr, "unknown --algo %q (known: %s, all)\n", algo, strings.Join(algoOrder, ", "))
+			os.Exit(2)
+		}
+		algos = []string{algo}
+	}
+
+	values, closer, err := loadInt32s(loader, dataset)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "read dataset:", err)
 		os.Exit(1)
 	}
+	defer closer.Close()
 
 	n := len(values)
-	dist := inferDistribution(*dataset)
 
-	lang := "go"
-	langVer := runtime.Version()
-	validate := !*noValidate
+	for _, name := range algos {
+		runAlgo(name, algoRegistry[name], values, n, dist, lang, langVer, validate, mode, warmup, reps, dataset, out, outFormat, acc, completed, progressEvery)
+	}
+}
+
+// resolveDtype returns dtypeFlag as-is when the caller passed --dtype
+// explicitly; otherwise it lets the dataset's magic header override
+// the "int32" default, so a --dataset-dir sweep can mix dtypes.
+func resolveDtype(dataset, dtypeFlag string, dtypeExplicit bool) (string, error) {
+	if dtypeExplicit {
+		return dtypeFlag, nil
+	}
+	detected, err := detectDtypeMagic(dataset)
+	if err != nil {
+		return "", err
+	}
+	if detected != "" {
+		return detected, nil
+	}
+	return dtypeFlag, nil
+}
+
+func writeSummaryIfEnabled(acc *summaryAccumulator, summaryOut string) {
+	if acc == nil {
+		return
+	}
+	if err := writeSummary(summaryOut, acc.stats()); err != nil {
+		fmt.Fprintln(os.Stderr, "write summary:", err)
+		os.Exit(1)
+	}
+}
 
+func runAlgo(name string, run sortAlgo, values []int32, n int, dist, lang, langVer string, validate bool, mode metricsMode, warmup, reps int, dataset, out, outFormat string, acc *summaryAccumulator, completed resumeSet, progressEvery int) {
 	// Warmup
-	for i := 0; i < *warmup; i++ {
+	for i := 0; i < warmup; i++ {
 		tmp := make([]int32, n)
 		copy(tmp, values)
-		sort.Slice(tmp, func(i, j int) bool { return tmp[i] < tmp[j] })
+		run(tmp)
 	}
 
+	tracker := newProgressTracker(20)
+	done := 0
+
 	// Measured
-	for rep := 0; rep < *reps; rep++ {
+	for rep := 0; rep < reps; rep++ {
+		if completed.has(dataset, name, rep) {
+			done++
+			continue
+		}
+
 		tmp := make([]int32, n)
 		copy(tmp, values)
 
+		before := captureSnapshot(mode)
 		t0 := time.Now()
-		sort.Slice(tmp, func(i, j int) bool { return tmp[i] < tmp[j] })
+		run(tmp)
 		elapsed := time.Since(t0)
+		after := captureSnapshot(mode)
+		rm := diffSnapshots(before, after, mode)
 
 		ok := true
 		if validate {
@@ -165,25 +355,44 @@ func main() {
 			"sort",
 			lang,
 			langVer,
-			*algo,
-			*dataset,
+			"int32",
+			name,
+			dataset,
 			dist,
 			fmt.Sprintf("%d", n),
-			fmt.Sprintf("%d", *warmup),
+			fmt.Sprintf("%d", warmup),
 			fmt.Sprintf("%d", rep),
 			fmt.Sprintf("%.3f", timeMs),
-			func() string {
-				if ok {
-					return "true"
-				}
-				return "false"
-			}(),
+			boolStr(ok),
+			metricField(rm.have, "%.3f", rm.userCPUMs),
+			metricField(rm.have, "%.3f", rm.sysCPUMs),
+			metricField(rm.have, "%d", rm.maxRSSKB),
+			metricField(rm.haveMem, "%d", rm.heapAllocDeltaBytes),
+			metricField(rm.haveMem, "%.3f", rm.gcPauseMs),
+			metricField(rm.haveMem, "%d", rm.numGC),
 		}
 
 		fmt.Println(strings.Join(row, ","))
-		if err := appendRow(*out, row); err != nil {
-			fmt.Fprintln(os.Stderr, "write csv:", err)
+		if err := appendRow(out, outFormat, row); err != nil {
+			fmt.Fprintln(os.Stderr, "write output:", err)
 			os.Exit(1)
 		}
+		acc.add(name, dataset, timeMs)
+
+		tracker.add(timeMs)
+		done++
+		if progressEvery > 0 && done%progressEvery == 0 {
+			reportProgress(dataset, name, done, reps, tracker)
+		}
+	}
+}
+
+// metricField renders a metrics value, or "" when it wasn't captured
+// (e.g. --metrics=none, or a full-only field under --metrics=basic) so
+// the CSV doesn't claim false precision.
+func metricField(have bool, format string, v interface{}) string {
+	if !have {
+		return ""
 	}
+	return fmt.Sprintf(format, v)
 }
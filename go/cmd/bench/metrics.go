@@ -0,0 +1,110 @@
+package main
+
+import (
+	"math"
+	"runtime"
+	"runtime/metrics"
+)
+
+// metricsMode controls how much per-rep instrumentation runAlgo pays
+// for. "none" keeps pure-timing runs free of extra syscalls and
+// runtime.ReadMemStats stop-the-world pauses.
+type metricsMode string
+
+const (
+	metricsNone  metricsMode = "none"
+	metricsBasic metricsMode = "basic"
+	metricsFull  metricsMode = "full"
+)
+
+func parseMetricsMode(s string) (metricsMode, bool) {
+	switch metricsMode(s) {
+	case metricsNone, metricsBasic, metricsFull:
+		return metricsMode(s), true
+	default:
+		return "", false
+	}
+}
+
+// repSnapshot is a cumulative, process-lifetime view of the counters
+// we care about. Two snapshots taken around a measured rep are
+// subtracted to get that rep's contribution.
+type repSnapshot struct {
+	rusage         rusageSnapshot
+	mem            runtime.MemStats
+	gcPauseSeconds float64
+	haveMem        bool
+}
+
+func captureSnapshot(mode metricsMode) repSnapshot {
+	var snap repSnapshot
+	if mode == metricsNone {
+		return snap
+	}
+	snap.rusage = readRusage()
+	if mode == metricsFull {
+		runtime.ReadMemStats(&snap.mem)
+		snap.gcPauseSeconds = readGCPauseSeconds()
+		snap.haveMem = true
+	}
+	return snap
+}
+
+// repMetrics holds the CSV-ready values for one measured rep.
+type repMetrics struct {
+	userCPUMs           float64
+	sysCPUMs            float64
+	maxRSSKB            int64
+	heapAllocDeltaBytes int64
+	gcPauseMs           float64
+	numGC               uint32
+	have                bool
+	haveMem             bool
+}
+
+func diffSnapshots(before, after repSnapshot, mode metricsMode) repMetrics {
+	if mode == metricsNone {
+		return repMetrics{}
+	}
+	rm := repMetrics{
+		have:      true,
+		userCPUMs: after.rusage.userCPUMs - before.rusage.userCPUMs,
+		sysCPUMs:  after.rusage.sysCPUMs - before.rusage.sysCPUMs,
+		maxRSSKB:  after.rusage.maxRSSKB,
+	}
+	if mode == metricsFull && before.haveMem && after.haveMem {
+		rm.heapAllocDeltaBytes = int64(after.mem.HeapAlloc) - int64(before.mem.HeapAlloc)
+		rm.gcPauseMs = (after.gcPauseSeconds - before.gcPauseSeconds) * 1000
+		rm.numGC = after.mem.NumGC - before.mem.NumGC
+		rm.haveMem = true
+	}
+	return rm
+}
+
+// readGCPauseSeconds returns the process-lifetime total GC stop-the-world
+// pause time, reconstructed from the /gc/pauses:seconds histogram
+// exposed by runtime/metrics (the histogram buckets individual pause
+// durations rather than exposing a running sum directly).
+func readGCPauseSeconds() float64 {
+	samples := []metrics.Sample{{Name: "/gc/pauses:seconds"}}
+	metrics.Read(samples)
+
+	h := samples[0].Value.Float64Histogram()
+	if h == nil {
+		return 0
+	}
+
+	var total float64
+	for i, count := range h.Counts {
+		if count == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		mid := lo
+		if !math.IsInf(hi, 1) {
+			mid = (lo + hi) / 2
+		}
+		total += mid * float64(count)
+	}
+	return total
+}
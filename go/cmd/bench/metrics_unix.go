@@ -0,0 +1,31 @@
+//go:build !windows
+
+package main
+
+import "syscall"
+
+// rusageSnapshot is the subset of getrusage(2) fields we report. Utime
+// and Stime are cumulative since process start, so callers diff two
+// snapshots to get a rep's CPU time. Maxrss is already the process's
+// peak resident set size, so it's reported as-is rather than diffed.
+//
+// Linux reports Maxrss in KB; Darwin reports it in bytes. We don't
+// correct for that here since the benchmarks this tool targets run on
+// Linux CI.
+type rusageSnapshot struct {
+	userCPUMs float64
+	sysCPUMs  float64
+	maxRSSKB  int64
+}
+
+func readRusage() rusageSnapshot {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return rusageSnapshot{}
+	}
+	return rusageSnapshot{
+		userCPUMs: float64(ru.Utime.Sec)*1000 + float64(ru.Utime.Usec)/1000,
+		sysCPUMs:  float64(ru.Stime.Sec)*1000 + float64(ru.Stime.Usec)/1000,
+		maxRSSKB:  int64(ru.Maxrss),
+	}
+}
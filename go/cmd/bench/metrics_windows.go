@@ -0,0 +1,17 @@
+//go:build windows
+
+package main
+
+// rusageSnapshot would normally come from GetProcessTimes (CPU) and
+// GetProcessMemoryInfo (RSS), but that path isn't wired up yet on
+// Windows. --metrics=basic/full still work, they just report zeros
+// here instead of failing.
+type rusageSnapshot struct {
+	userCPUMs float64
+	sysCPUMs  float64
+	maxRSSKB  int64
+}
+
+func readRusage() rusageSnapshot {
+	return rusageSnapshot{}
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// sortAlgo is the signature every registry entry must satisfy: sort a
+// into non-decreasing order in place.
+type sortAlgo func(a []int32)
+
+// algoRegistry maps the --algo flag value to an implementation. Map
+// iteration order is not defined, so --algo all runs entries in the
+// order given by algoOrder.
+var algoRegistry = map[string]sortAlgo{
+	"builtin":  builtinSort,
+	"radix":    radixSortInt32,
+	"pdq":      pdqSort,
+	"heap":     heapSort,
+	"parmerge": parallelMergeSort,
+}
+
+// algoOrder is the deterministic iteration order for --algo all.
+var algoOrder = []string{"builtin", "radix", "pdq", "heap", "parmerge"}
+
+func builtinSort(a []int32) {
+	sortInt32sStd(a)
+}
+
+// --- radix sort (LSD, 11-bit passes) ---
+
+func radixSortInt32(a []int32) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+
+	const passBits = 11
+	const buckets = 1 << passBits
+	const mask = buckets - 1
+
+	src := make([]uint32, n)
+	for i, v := range a {
+		// Flip the sign bit so two's-complement ordering matches
+		// unsigned ordering for the duration of the radix passes.
+		src[i] = uint32(v) ^ 0x80000000
+	}
+	dst := make([]uint32, n)
+
+	var counts [buckets]int
+	for shift := uint(0); shift < 32; shift += passBits {
+		for i := range counts {
+			counts[i] = 0
+		}
+		for _, v := range src {
+			counts[(v>>shift)&mask]++
+		}
+		sum := 0
+		for i, c := range counts {
+			counts[i] = sum
+			sum += c
+		}
+		for _, v := range src {
+			k := (v >> shift) & mask
+			dst[counts[k]] = v
+			counts[k]++
+		}
+		src, dst = dst, src
+	}
+
+	for i, v := range src {
+		a[i] = int32(v ^ 0x80000000)
+	}
+}
+
+// --- pdqsort (pattern-defeating quicksort, simplified) ---
+
+const pdqInsertionThreshold = 24
+
+func pdqSort(a []int32) {
+	if len(a) < 2 {
+		return
+	}
+	maxDepth := 2 * bitLen(len(a))
+	pdqSortLoop(a, maxDepth)
+}
+
+func pdqSortLoop(a []int32, depth int) {
+	for len(a) > pdqInsertionThreshold {
+		if depth == 0 {
+			heapSort(a)
+			return
+		}
+		depth--
+
+		pivot := medianOfThree(a)
+		lt, gt := partitionThreeWay(a, pivot)
+
+		// Recurse into the smaller side, loop on the larger one to
+		// bound stack depth at O(log n).
+		if lt < len(a)-gt {
+			pdqSortLoop(a[:lt], depth)
+			a = a[gt:]
+		} else {
+			pdqSortLoop(a[gt:], depth)
+			a = a[:lt]
+		}
+	}
+	insertionSort(a)
+}
+
+func medianOfThree(a []int32) int32 {
+	n := len(a)
+	lo, mid, hi := 0, n/2, n-1
+	if a[mid] < a[lo] {
+		a[mid], a[lo] = a[lo], a[mid]
+	}
+	if a[hi] < a[lo] {
+		a[hi], a[lo] = a[lo], a[hi]
+	}
+	if a[hi] < a[mid] {
+		a[hi], a[mid] = a[mid], a[hi]
+	}
+	return a[mid]
+}
+
+// partitionThreeWay partitions a into [<pivot][==pivot][>pivot] (Dutch
+// national flag) and returns the bounds of the equal region so the
+// caller can skip already-sorted elements.
+func partitionThreeWay(a []int32, pivot int32) (lt, gt int) {
+	lt, i, gt := 0, 0, len(a)
+	for i < gt {
+		switch {
+		case a[i] < pivot:
+			a[lt], a[i] = a[i], a[lt]
+			lt++
+			i++
+		case a[i] > pivot:
+			gt--
+			a[i], a[gt] = a[gt], a[i]
+		default:
+			i++
+		}
+	}
+	return lt, gt
+}
+
+func insertionSort(a []int32) {
+	for i := 1; i < len(a); i++ {
+		v := a[i]
+		j := i - 1
+		for j >= 0 && a[j] > v {
+			a[j+1] = a[j]
+			j--
+		}
+		a[j+1] = v
+	}
+}
+
+func bitLen(n int) int {
+	b := 0
+	for n > 0 {
+		n >>= 1
+		b++
+	}
+	return b
+}
+
+// --- binary heap sort ---
+
+func heapSort(a []int32) {
+	n := len(a)
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(a, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		a[0], a[end] = a[end], a[0]
+		siftDown(a, 0, end)
+	}
+}
+
+func siftDown(a []int32, root, n int) {
+	for {
+		largest := root
+		l, r := 2*root+1, 2*root+2
+		if l < n && a[l] > a[largest] {
+			largest = l
+		}
+		if r < n && a[r] > a[largest] {
+			largest = r
+		}
+		if largest == root {
+			return
+		}
+		a[root], a[largest] = a[largest], a[root]
+		root = largest
+	}
+}
+
+// --- parallel merge sort ---
+
+// parallelMergeSort fans out len(a) across runtime.NumCPU() initial
+// partitions, sorts each concurrently, then merges the results
+// pairwise back down to one slice.
+func parallelMergeSort(a []int32) {
+	n := len(a)
+	if n < 2 {
+		return
+	}
+
+	workers := runtime.NumCPU()
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	chunkSize := (n + workers - 1) / workers
+	chunks := make([][]int32, 0, workers)
+	for start := 0; start < n; start += chunkSize {
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		chunks = append(chunks, a[start:end])
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range chunks {
+		c := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			insertionSortOrPdq(c)
+		}()
+	}
+	wg.Wait()
+
+	for len(chunks) > 1 {
+		merged := make([][]int32, 0, (len(chunks)+1)/2)
+		var mwg sync.WaitGroup
+		results := make([][]int32, (len(chunks)+1)/2)
+		for i := 0; i < len(chunks); i += 2 {
+			if i+1 == len(chunks) {
+				results[i/2] = chunks[i]
+				continue
+			}
+			i := i
+			mwg.Add(1)
+			go func() {
+				defer mwg.Done()
+				results[i/2] = mergeSorted(chunks[i], chunks[i+1])
+			}()
+		}
+		mwg.Wait()
+		merged = results
+		chunks = merged
+	}
+
+	copy(a, chunks[0])
+}
+
+func insertionSortOrPdq(a []int32) {
+	if len(a) <= pdqInsertionThreshold {
+		insertionSort(a)
+		return
+	}
+	pdqSort(a)
+}
+
+func mergeSorted(a, b []int32) []int32 {
+	out := make([]int32, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if a[i] <= b[j] {
+			out = append(out, a[i])
+			i++
+		} else {
+			out = append(out, b[j])
+			j++
+		}
+	}
+	out = append(out, a[i:]...)
+	out = append(out, b[j:]...)
+	return out
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	summaryTrimFraction = 0.10
+	bootstrapIterations = 1000
+	bootstrapConfidence = 0.95
+)
+
+// summaryKey groups raw reps the same way the summary is reported:
+// per (algo, dataset).
+type summaryKey struct {
+	Algo    string
+	Dataset string
+}
+
+// summaryAccumulator collects per-rep time_ms values as they're
+// recorded so a --summary run can report stats once all reps finish.
+// A nil *summaryAccumulator is a valid no-op receiver, so callers can
+// unconditionally call add without checking --summary first.
+type summaryAccumulator struct {
+	order []summaryKey
+	times map[summaryKey][]float64
+}
+
+func newSummaryAccumulator() *summaryAccumulator {
+	return &summaryAccumulator{times: make(map[summaryKey][]float64)}
+}
+
+func (s *summaryAccumulator) add(algo, dataset string, timeMs float64) {
+	if s == nil {
+		return
+	}
+	k := summaryKey{Algo: algo, Dataset: dataset}
+	if _, seen := s.times[k]; !seen {
+		s.order = append(s.order, k)
+	}
+	s.times[k] = append(s.times[k], timeMs)
+}
+
+// stats computes the summary row for every (algo, dataset) group, in
+// the order groups were first seen.
+func (s *summaryAccumulator) stats() []summaryStats {
+	if s == nil {
+		return nil
+	}
+	out := make([]summaryStats, 0, len(s.order))
+	for _, k := range s.order {
+		out = append(out, computeSummaryStats(k.Algo, k.Dataset, s.times[k]))
+	}
+	return out
+}
+
+type summaryStats struct {
+	Algo          string
+	Dataset       string
+	N             int
+	MeanMs        float64
+	MedianMs      float64
+	StddevMs      float64
+	MinMs         float64
+	MaxMs         float64
+	P95Ms         float64
+	P99Ms         float64
+	TrimmedMeanMs float64
+	CILowMs       float64
+	CIHighMs      float64
+}
+
+func computeSummaryStats(algo, dataset string, samples []float64) summaryStats {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	mean := meanOf(sorted)
+	ciLow, ciHigh := bootstrapMeanCI(samples, bootstrapIterations, bootstrapConfidence)
+
+	return summaryStats{
+		Algo:          algo,
+		Dataset:       dataset,
+		N:             len(sorted),
+		MeanMs:        mean,
+		MedianMs:      percentile(sorted, 50),
+		StddevMs:      stddevOf(sorted, mean),
+		MinMs:         minOf(sorted),
+		MaxMs:         maxOf(sorted),
+		P95Ms:         percentile(sorted, 95),
+		P99Ms:         percentile(sorted, 99),
+		TrimmedMeanMs: trimmedMean(sorted, summaryTrimFraction),
+		CILowMs:       ciLow,
+		CIHighMs:      ciHigh,
+	}
+}
+
+func meanOf(a []float64) float64 {
+	if len(a) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range a {
+		sum += v
+	}
+	return sum / float64(len(a))
+}
+
+func stddevOf(a []float64, mean float64) float64 {
+	if len(a) < 2 {
+		return 0
+	}
+	var sumSq float64
+	for _, v := range a {
+		d := v - mean
+		sumSq += d * d
+	}
+	return math.Sqrt(sumSq / float64(len(a)-1))
+}
+
+func minOf(sorted []float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[0]
+}
+
+func maxOf(sorted []float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[len(sorted)-1]
+}
+
+// percentile uses linear interpolation between closest ranks (sorted
+// must already be ascending).
+func percentile(sorted []float64, p float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n == 1 {
+		return sorted[0]
+	}
+	rank := p / 100 * float64(n-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := rank - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
+
+// trimmedMean drops the top and bottom trimFrac of sorted before
+// averaging the rest.
+func trimmedMean(sorted []float64, trimFrac float64) float64 {
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	k := int(float64(n) * trimFrac)
+	trimmed := sorted[k : n-k]
+	if len(trimmed) == 0 {
+		trimmed = sorted
+	}
+	return meanOf(trimmed)
+}
+
+// bootstrapMeanCI resamples samples with replacement `iterations`
+// times, computing the mean of each resample, and returns the
+// confidence-interval bounds of that distribution of means.
+func bootstrapMeanCI(samples []float64, iterations int, confidence float64) (lo, hi float64) {
+	n := len(samples)
+	if n == 0 {
+		return 0, 0
+	}
+	rng := rand.New(rand.NewSource(int64(n) * 2654435761))
+
+	means := make([]float64, iterations)
+	for i := 0; i < iterations; i++ {
+		var sum float64
+		for j := 0; j < n; j++ {
+			sum += samples[rng.Intn(n)]
+		}
+		means[i] = sum / float64(n)
+	}
+	sort.Float64s(means)
+
+	alpha := (1 - confidence) / 2
+	return percentile(means, alpha*100), percentile(means, (1-alpha)*100)
+}
+
+var summaryHeader = []string{
+	"algo",
+	"dataset_file",
+	"n",
+	"mean_ms",
+	"median_ms",
+	"stddev_ms",
+	"min_ms",
+	"max_ms",
+	"p95_ms",
+	"p99_ms",
+	"trimmed_mean_ms",
+	"ci95_low_ms",
+	"ci95_high_ms",
+}
+
+func summaryRow(s summaryStats) []string {
+	return []string{
+		s.Algo,
+		s.Dataset,
+		fmt.Sprintf("%d", s.N),
+		fmt.Sprintf("%.4f", s.MeanMs),
+		fmt.Sprintf("%.4f", s.MedianMs),
+		fmt.Sprintf("%.4f", s.StddevMs),
+		fmt.Sprintf("%.4f", s.MinMs),
+		fmt.Sprintf("%.4f", s.MaxMs),
+		fmt.Sprintf("%.4f", s.P95Ms),
+		fmt.Sprintf("%.4f", s.P99Ms),
+		fmt.Sprintf("%.4f", s.TrimmedMeanMs),
+		fmt.Sprintf("%.4f", s.CILowMs),
+		fmt.Sprintf("%.4f", s.CIHighMs),
+	}
+}
+
+// writeSummary writes stats to path as CSV, or as NDJSON when path
+// ends in .ndjson.
+func writeSummary(path string, stats []summaryStats) error {
+	if err := ensureParentDir(path); err != nil {
+		return err
+	}
+	if isNDJSONPath(path) {
+		return writeSummaryNDJSON(path, stats)
+	}
+	return writeSummaryCSV(path, stats)
+}
+
+func isNDJSONPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".ndjson")
+}
+
+func writeSummaryCSV(path string, stats []summaryStats) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	defer w.Flush()
+
+	if err := w.Write(summaryHeader); err != nil {
+		return err
+	}
+	for _, s := range stats {
+		if err := w.Write(summaryRow(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeSummaryNDJSON(path string, stats []summaryStats) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for _, s := range stats {
+		obj := make(map[string]string, len(summaryHeader))
+		row := summaryRow(s)
+		for i, col := range summaryHeader {
+			obj[col] = row[i]
+		}
+		line, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// discoverDatasets walks dir for files whose base name matches
+// pattern (a filepath.Match glob, e.g. "*.bin"), returning matches in
+// a stable, sorted order.
+func discoverDatasets(dir, pattern string) ([]string, error) {
+	var found []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		matched, err := filepath.Match(pattern, d.Name())
+		if err != nil {
+			return err
+		}
+		if matched {
+			found = append(found, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(found)
+	return found, nil
+}
+
+func shuffleStrings(a []string) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	rng.Shuffle(len(a), func(i, j int) { a[i], a[j] = a[j], a[i] })
+}
+
+// resumeKey identifies one measured rep that may already be recorded
+// in a prior --out run.
+type resumeKey struct {
+	Dataset string
+	Algo    string
+	Rep     int
+}
+
+// resumeSet is the set of (dataset_file, algo, rep_idx) tuples already
+// present in --out. A nil resumeSet (the --resume=false case) reports
+// nothing as done.
+type resumeSet map[resumeKey]struct{}
+
+func (r resumeSet) has(dataset, algo string, rep int) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r[resumeKey{dataset, algo, rep}]
+	return ok
+}
+
+// buildResumeSet reads the existing --out file (if any) in the given
+// format and returns the set of reps already recorded, so a sweep can
+// skip them on restart.
+func buildResumeSet(path, format string) (resumeSet, error) {
+	if !fileExists(path) {
+		return resumeSet{}, nil
+	}
+	switch format {
+	case "", "csv":
+		return buildResumeSetCSV(path)
+	case "ndjson":
+		return buildResumeSetNDJSON(path)
+	default:
+		return nil, fmt.Errorf("unknown --out-format %q (known: csv, ndjson)", format)
+	}
+}
+
+func buildResumeSetCSV(path string) (resumeSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	header, err := r.Read()
+	if err == io.EOF {
+		return resumeSet{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[name] = i
+	}
+	datasetCol, algoCol, repCol := col["dataset_file"], col["algo"], col["rep_idx"]
+
+	set := resumeSet{}
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rep, convErr := strconv.Atoi(rec[repCol])
+		if convErr != nil {
+			continue
+		}
+		set[resumeKey{rec[datasetCol], rec[algoCol], rep}] = struct{}{}
+	}
+	return set, nil
+}
+
+func buildResumeSetNDJSON(path string) (resumeSet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	set := resumeSet{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var obj map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+			continue
+		}
+		rep, convErr := strconv.Atoi(obj["rep_idx"])
+		if convErr != nil {
+			continue
+		}
+		set[resumeKey{obj["dataset_file"], obj["algo"], rep}] = struct{}{}
+	}
+	return set, scanner.Err()
+}
+
+// progressTracker keeps a rolling window of recent rep durations so a
+// sweep can report an ETA based on the rolling median rather than a
+// single noisy sample.
+type progressTracker struct {
+	window []float64
+	next   int
+	filled int
+}
+
+func newProgressTracker(window int) *progressTracker {
+	return &progressTracker{window: make([]float64, window)}
+}
+
+func (p *progressTracker) add(ms float64) {
+	p.window[p.next] = ms
+	p.next = (p.next + 1) % len(p.window)
+	if p.filled < len(p.window) {
+		p.filled++
+	}
+}
+
+func (p *progressTracker) medianMs() float64 {
+	if p.filled == 0 {
+		return 0
+	}
+	s := append([]float64(nil), p.window[:p.filled]...)
+	sort.Float64s(s)
+	mid := len(s) / 2
+	if len(s)%2 == 0 {
+		return (s[mid-1] + s[mid]) / 2
+	}
+	return s[mid]
+}
+
+// reportProgress writes one ETA line to stderr, based on the tracker's
+// rolling median rep time times the reps still to go.
+func reportProgress(dataset, algo string, done, total int, tracker *progressTracker) {
+	remaining := total - done
+	etaMs := tracker.medianMs() * float64(remaining)
+	eta := time.Duration(etaMs * float64(time.Millisecond)).Round(time.Millisecond)
+	fmt.Fprintf(os.Stderr, "[progress] dataset=%s algo=%s rep=%d/%d eta=%s\n",
+		filepath.Base(dataset), algo, done, total, eta)
+}